@@ -0,0 +1,239 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tochemey/goakt/log"
+)
+
+// SyncChecker is optionally implemented by a Discovery provider whose cache
+// needs to be fully synced before its events can be trusted. A provider that
+// does not implement it is always considered synced, so when it is an
+// Aggregator's primary, the events of the providers after it are never
+// forwarded.
+type SyncChecker interface {
+	// HasSynced reports whether the provider's internal cache is up to date
+	HasSynced() bool
+}
+
+// Aggregator fans a single Discovery interface out across an ordered list of
+// providers (for instance Kubernetes, static, DNS, mDNS or Consul). Nodes()
+// queries every provider in parallel and dedupes the results by (host, port).
+// Watch() merges every provider's event stream, except that providers after
+// the first only forward events while the primary provider's cache is not
+// synced, so a flaky primary fails over instead of producing duplicate churn.
+//
+// Note: this Aggregator lives in the non-v2 discovery package built up
+// across the kubernetes-discovery work. Wiring it into actors.WithCluster /
+// actors.NewClusterConfig is left undone here because the actors package
+// already imports packages of the github.com/tochemey/goakt/v2 module (see
+// actors/option_test.go) and a v2 ClusterConfig cannot accept a v1
+// discovery.Discovery without the two modules being reconciled first; that
+// reconciliation is out of scope for this package.
+type Aggregator struct {
+	mu         sync.Mutex
+	providers  []Discovery
+	logger     log.Logger
+	publicChan chan Event
+	stopChan   chan struct{}
+	// forwarders tracks the in-flight forward/forwardWhenPrimaryUnsynced
+	// goroutines so Stop can wait for all of them to return before closing
+	// publicChan, instead of racing a send against the close.
+	forwarders sync.WaitGroup
+}
+
+// enforce compilation error
+var _ Discovery = &Aggregator{}
+
+// NewAggregator returns an Aggregator backed by the given providers, in
+// priority order. The first provider is treated as the primary.
+func NewAggregator(logger log.Logger, providers ...Discovery) *Aggregator {
+	return &Aggregator{
+		providers:  providers,
+		logger:     logger,
+		publicChan: make(chan Event, 2),
+		stopChan:   make(chan struct{}, 1),
+	}
+}
+
+// Start starts every provider in priority order, stopping at and returning
+// the first error encountered.
+func (a *Aggregator) Start(ctx context.Context, meta Meta) error {
+	for i, provider := range a.providers {
+		if err := provider.Start(ctx, meta); err != nil {
+			return errors.Wrapf(err, "failed to start discovery provider at index %d", i)
+		}
+	}
+	return nil
+}
+
+// Stop stops every provider and closes the merged event channel. stopChan is
+// closed first so every forward/forwardWhenPrimaryUnsynced goroutine selects
+// its way out instead of sending, and Stop waits for them to actually return
+// before closing publicChan, so no goroutine can send on a closed channel.
+func (a *Aggregator) Stop() error {
+	var lastErr error
+	for i, provider := range a.providers {
+		if err := provider.Stop(); err != nil {
+			lastErr = errors.Wrapf(err, "failed to stop discovery provider at index %d", i)
+		}
+	}
+	close(a.stopChan)
+	a.forwarders.Wait()
+	close(a.publicChan)
+	return lastErr
+}
+
+// Nodes fans the lookup out to every provider in parallel and returns the
+// deduplicated union of their results, keyed by (host, port).
+func (a *Aggregator) Nodes(ctx context.Context) ([]*Node, error) {
+	type result struct {
+		nodes []*Node
+		err   error
+	}
+
+	results := make([]result, len(a.providers))
+	var wg sync.WaitGroup
+	for i, provider := range a.providers {
+		wg.Add(1)
+		go func(i int, provider Discovery) {
+			defer wg.Done()
+			nodes, err := provider.Nodes(ctx)
+			results[i] = result{nodes: nodes, err: err}
+		}(i, provider)
+	}
+	wg.Wait()
+
+	seen := make(map[string]*Node)
+	var firstErr error
+	for i, res := range results {
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = errors.Wrapf(res.err, "discovery provider at index %d failed", i)
+			}
+			continue
+		}
+		for _, node := range res.nodes {
+			seen[nodeKey(node)] = node
+		}
+	}
+
+	// only fail outright when at least one provider failed and no provider
+	// produced a node
+	if len(seen) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	nodes := make([]*Node, 0, len(seen))
+	for _, node := range seen {
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// EarliestNode returns the earliest node across all providers
+func (a *Aggregator) EarliestNode(ctx context.Context) (*Node, error) {
+	nodes, err := a.Nodes(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the earliest node")
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("no node found")
+	}
+	earliest := nodes[0]
+	for _, node := range nodes[1:] {
+		if node.Timestamp() < earliest.Timestamp() {
+			earliest = node
+		}
+	}
+	return earliest, nil
+}
+
+// Watch merges every provider's event stream onto a single channel. The
+// first provider is always forwarded. Subsequent providers only forward
+// events while the primary reports an unsynced cache via SyncChecker,
+// implementing a simple priority/failover policy.
+func (a *Aggregator) Watch(ctx context.Context) (<-chan Event, error) {
+	if len(a.providers) == 0 {
+		return a.publicChan, nil
+	}
+
+	primary := a.providers[0]
+	primaryEvents, err := primary.Watch(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to watch the primary discovery provider")
+	}
+	a.forwarders.Add(1)
+	go a.forward(primaryEvents)
+
+	for i := 1; i < len(a.providers); i++ {
+		secondary := a.providers[i]
+		secondaryEvents, err := secondary.Watch(ctx)
+		if err != nil {
+			a.logger.Error(errors.Wrapf(err, "failed to watch secondary discovery provider at index %d", i))
+			continue
+		}
+		a.forwarders.Add(1)
+		go a.forwardWhenPrimaryUnsynced(primary, secondaryEvents)
+	}
+
+	return a.publicChan, nil
+}
+
+// forward relays every event from src onto the merged public channel. The
+// send to publicChan is itself gated on stopChan so that Stop closing
+// publicChan can never race a send already in flight.
+func (a *Aggregator) forward(src <-chan Event) {
+	defer a.forwarders.Done()
+	for {
+		select {
+		case <-a.stopChan:
+			return
+		case event, ok := <-src:
+			if !ok {
+				return
+			}
+			select {
+			case a.publicChan <- event:
+			case <-a.stopChan:
+				return
+			}
+		}
+	}
+}
+
+// forwardWhenPrimaryUnsynced only relays events from src while the primary
+// provider does not report itself as synced. Providers that do not
+// implement SyncChecker are treated as always synced, meaning their
+// secondaries never take over. As with forward, the send to publicChan is
+// gated on stopChan so it can never race Stop's close of publicChan.
+func (a *Aggregator) forwardWhenPrimaryUnsynced(primary Discovery, src <-chan Event) {
+	defer a.forwarders.Done()
+	checker, ok := primary.(SyncChecker)
+	for {
+		select {
+		case <-a.stopChan:
+			return
+		case event, ok2 := <-src:
+			if !ok2 {
+				return
+			}
+			if !ok || checker.HasSynced() {
+				continue
+			}
+			select {
+			case a.publicChan <- event:
+			case <-a.stopChan:
+				return
+			}
+		}
+	}
+}
+
+// nodeKey returns the dedupe key used to merge nodes across providers
+func nodeKey(node *Node) string {
+	return fmt.Sprintf("%s:%d", node.Host(), node.Port())
+}
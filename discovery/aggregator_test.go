@@ -0,0 +1,131 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/tochemey/goakt/log"
+)
+
+// stubProvider is a minimal Discovery + SyncChecker used to exercise the
+// Aggregator without standing up a real provider.
+type stubProvider struct {
+	nodes  []*Node
+	events chan Event
+	synced bool
+}
+
+func (s *stubProvider) Start(context.Context, Meta) error { return nil }
+func (s *stubProvider) Stop() error                       { return nil }
+func (s *stubProvider) Nodes(context.Context) ([]*Node, error) {
+	return s.nodes, nil
+}
+
+func (s *stubProvider) EarliestNode(context.Context) (*Node, error) {
+	if len(s.nodes) == 0 {
+		return nil, errors.New("no nodes configured on stub provider")
+	}
+	return s.nodes[0], nil
+}
+
+func (s *stubProvider) Watch(context.Context) (<-chan Event, error) {
+	return s.events, nil
+}
+
+func (s *stubProvider) HasSynced() bool { return s.synced }
+
+var (
+	_ Discovery   = &stubProvider{}
+	_ SyncChecker = &stubProvider{}
+)
+
+// plainProvider is a minimal Discovery that deliberately does not implement
+// SyncChecker, used to exercise the no-SyncChecker primary path.
+type plainProvider struct {
+	nodes  []*Node
+	events chan Event
+}
+
+func (p *plainProvider) Start(context.Context, Meta) error      { return nil }
+func (p *plainProvider) Stop() error                            { return nil }
+func (p *plainProvider) Nodes(context.Context) ([]*Node, error) { return p.nodes, nil }
+func (p *plainProvider) EarliestNode(context.Context) (*Node, error) {
+	return nil, errors.New("not implemented")
+}
+func (p *plainProvider) Watch(context.Context) (<-chan Event, error) { return p.events, nil }
+
+var _ Discovery = &plainProvider{}
+
+func TestAggregatorNodesDedupesByHostPort(t *testing.T) {
+	n1 := NewNode("a", "10.0.0.1", 3000, 100, nil)
+	n2 := NewNode("b", "10.0.0.1", 3000, 200, nil) // same host:port as n1
+	n3 := NewNode("c", "10.0.0.2", 3000, 300, nil)
+
+	p1 := &stubProvider{nodes: []*Node{n1, n3}}
+	p2 := &stubProvider{nodes: []*Node{n2}}
+
+	agg := NewAggregator(log.DefaultLogger, p1, p2)
+	nodes, err := agg.Nodes(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+}
+
+func TestAggregatorSuppressesSecondaryWhenPrimarySynced(t *testing.T) {
+	primaryEvents := make(chan Event, 1)
+	secondaryEvents := make(chan Event, 1)
+	primary := &stubProvider{events: primaryEvents, synced: true}
+	secondary := &stubProvider{events: secondaryEvents, synced: true}
+
+	agg := NewAggregator(log.DefaultLogger, primary, secondary)
+	merged, err := agg.Watch(context.Background())
+	assert.NoError(t, err)
+
+	secondaryEvents <- &NodeAdded{Node: NewNode("a", "10.0.0.1", 3000, 100, nil)}
+
+	select {
+	case <-merged:
+		t.Fatal("expected the secondary's event to be suppressed while the primary reports synced")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.NoError(t, agg.Stop())
+}
+
+func TestAggregatorSuppressesSecondaryWhenPrimaryNotSyncChecker(t *testing.T) {
+	primaryEvents := make(chan Event, 1)
+	secondaryEvents := make(chan Event, 1)
+	primary := &plainProvider{events: primaryEvents}
+	secondary := &stubProvider{events: secondaryEvents, synced: true}
+
+	agg := NewAggregator(log.DefaultLogger, primary, secondary)
+	merged, err := agg.Watch(context.Background())
+	assert.NoError(t, err)
+
+	secondaryEvents <- &NodeAdded{Node: NewNode("a", "10.0.0.1", 3000, 100, nil)}
+
+	select {
+	case <-merged:
+		t.Fatal("expected the secondary's event to be suppressed when the primary does not implement SyncChecker")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.NoError(t, agg.Stop())
+}
+
+func TestAggregatorStopDoesNotPanicWhileForwarding(t *testing.T) {
+	events := make(chan Event, 1)
+	p1 := &stubProvider{nodes: []*Node{NewNode("a", "10.0.0.1", 3000, 100, nil)}, events: events}
+
+	agg := NewAggregator(log.DefaultLogger, p1)
+	_, err := agg.Watch(context.Background())
+	assert.NoError(t, err)
+
+	events <- &NodeAdded{Node: NewNode("a", "10.0.0.1", 3000, 100, nil)}
+
+	assert.NotPanics(t, func() {
+		assert.NoError(t, agg.Stop())
+	})
+}
@@ -0,0 +1,12 @@
+package discovery
+
+// ProviderDegraded is emitted when a discovery provider can no longer trust
+// its own view of cluster membership, for instance because its informer
+// cache lost sync with the API server and is retrying with backoff.
+// Consumers may use it to pause decisions that depend on an accurate
+// membership view until a subsequent NodeAdded/NodeModified/NodeRemoved
+// restores confidence.
+type ProviderDegraded struct {
+	// Reason describes why the provider considers itself degraded
+	Reason string
+}
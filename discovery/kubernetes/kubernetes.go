@@ -2,7 +2,10 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,20 +13,56 @@ import (
 	"github.com/tochemey/goakt/discovery"
 	"github.com/tochemey/goakt/log"
 	"go.uber.org/atomic"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	v1 "k8s.io/api/core/v1"
 	k8meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 )
 
 const (
-	Namespace     string = "namespace"
-	PodLabels            = "pod_labels"
-	LabelSelector        = "label_selector"
-	PortName             = "port_name"
+	Namespace             string = "namespace"
+	PodLabels                    = "pod_labels"
+	LabelSelector                = "label_selector"
+	PortName                     = "port_name"
+	LeaseNamespace               = "lease_namespace"
+	LeaseDurationSeconds         = "lease_duration_seconds"
+	UseCRD                       = "use_crd"
+	CRDGroup                     = "crd_group"
+	CRDVersion                   = "crd_version"
+	CRDResource                  = "crd_resource"
+	CRDName                      = "crd_name"
+	NodesPageSize                = "nodes_page_size"
+	MaxSyncBackoffSeconds        = "max_sync_backoff_seconds"
+	StableIdentity               = "stable_identity"
+)
+
+const (
+	// defaultLeaseNamespace is the namespace kubernetes itself uses to host the
+	// per-node Lease objects that back the NodeLease feature
+	defaultLeaseNamespace = "kube-node-lease"
+	// defaultLeaseDurationSeconds mirrors the kubelet's default node lease duration
+	defaultLeaseDurationSeconds = 40
+	// defaultCRDGroup, defaultCRDVersion and defaultCRDResource describe the
+	// built-in GoAktCluster custom resource when none is configured
+	defaultCRDGroup    = "goakt.io"
+	defaultCRDVersion  = "v1"
+	defaultCRDResource = "goaktclusters"
+	// defaultNodesPageSize bounds each page of the server-side pod list used
+	// by Nodes when it falls through to the paginated path
+	defaultNodesPageSize = 500
+	// defaultMaxSyncBackoff caps the retry backoff used by watchPods when the
+	// informer cache fails to sync
+	defaultMaxSyncBackoff = 30 * time.Second
 )
 
 // Option represents the kubernetes provider option
@@ -38,19 +77,96 @@ type Option struct {
 	LabelSelector string
 	// Specifies the port name
 	PortName string
+	// LeaseNamespace specifies the namespace where the coordination.k8s.io/v1
+	// Lease objects used as an additional liveness signal live. Defaults to
+	// the kube-node-lease namespace when unset.
+	LeaseNamespace string
+	// LeaseDurationSeconds specifies how long a lease can go unrenewed before
+	// the owning pod is considered unreachable. Defaults to defaultLeaseDurationSeconds.
+	LeaseDurationSeconds int64
+	// UseCRD turns on the CRD-backed membership source. When set, a
+	// GoAktCluster custom resource is watched in addition to the raw pod
+	// label selector, letting operators manage topology declaratively.
+	UseCRD bool
+	// CRDGroupVersionResource identifies the GoAktCluster custom resource to
+	// watch. Defaults to goakt.io/v1, resource goaktclusters.
+	CRDGroupVersionResource schema.GroupVersionResource
+	// CRDName is the name of the GoAktCluster object to watch within NameSpace
+	CRDName string
+	// SortBy orders the result of Nodes(). When unset, nodes are sorted by
+	// their timestamp, oldest first, mirroring EarliestNode's prior behavior.
+	// This mirrors the GetFirstPod(... sortBy) pattern from kubectl's factory.
+	SortBy func([]*discovery.Node) sort.Interface
+	// NodesPageSize bounds how many pods are fetched per page when Nodes
+	// falls through to the paginated server-side list. Defaults to
+	// defaultNodesPageSize.
+	NodesPageSize int64
+	// MaxSyncBackoff caps how long watchPods waits between retries when the
+	// informer cache fails to sync. Defaults to defaultMaxSyncBackoff.
+	MaxSyncBackoff time.Duration
+	// StableIdentity, when true, additionally tags the node with the pod's
+	// UID (tag "pod_uid"). The pod UID is never used as the node ID itself:
+	// node identity here is always the pod name,
+	// because the informer keys ADD/UPDATE/DELETE by object identity, so a
+	// pod recreated with the same name would otherwise look like a
+	// NodeRemoved of the old UID followed by a NodeAdded of the new one,
+	// instead of a single NodeModified. The pod_uid tag lets a caller that
+	// reads node tags detect that a recreation happened, without losing the
+	// NodeModified-on-recreate behavior that name-based identity gives for
+	// free.
+	StableIdentity bool
 }
 
 // Kubernetes represents the kubernetes provider
 type Kubernetes struct {
 	option    *Option
 	k8sClient *kubernetes.Clientset
-	mu        sync.Mutex
+	// dynamicClient is only set when option.UseCRD is enabled and backs the
+	// GoAktCluster custom resource informer
+	dynamicClient dynamic.Interface
+	mu            sync.Mutex
 
 	stopChan   chan struct{}
 	publicChan chan discovery.Event
+	// closeMu guards publicChan against the classic close-while-sending
+	// panic: sendEvent holds the read lock for the duration of its send,
+	// and Stop takes the write lock (which waits for every in-flight
+	// sendEvent to finish) before closing publicChan and setting closed.
+	closeMu sync.RWMutex
+	closed  bool
 	// states whether the actor system has started or not
 	isInitialized *atomic.Bool
 	logger        log.Logger
+
+	// knownPods tracks the pods we have already turned into nodes so that an
+	// expiring lease or a flipped readiness gate can resolve back to a node
+	// without waiting on the pod informer to observe the same transition
+	knownPods map[string]*v1.Pod
+	// podsByNode indexes the names of knownPods by their Spec.NodeName. A
+	// kube-node-lease Lease is named after the Kubernetes Node it belongs to,
+	// not after any one pod, and a single node can host several of our pods,
+	// so a lease expiry has to fan out to every pod scheduled on that node.
+	podsByNode map[string]map[string]struct{}
+	// leaseRenewals tracks the last observed renew time for each node lease,
+	// keyed by lease name, which is the Kubernetes Node name
+	leaseRenewals map[string]time.Time
+	// podsSynced reports whether the pod informer cache is currently synced.
+	// It backs HasSynced, letting a discovery.Aggregator fail over to a
+	// secondary provider while this one is still warming up.
+	podsSynced *atomic.Bool
+	// podLister serves Nodes() from the informer cache while it is synced,
+	// avoiding a round trip to the API server on the hot path
+	podLister corelisters.PodLister
+	// handlerPanics counts how many times an informer event handler has
+	// recovered from a panic, surfaced here until the telemetry package
+	// exposes a dedicated counter for it
+	handlerPanics *atomic.Int64
+	// crMemberNodes caches the nodes last resolved for each GoAktCluster
+	// object, keyed by CR name, so its DeleteFunc can emit NodeRemoved for
+	// exactly the nodes that were last known to belong to it instead of
+	// re-listing pods that are very likely still running after the CR itself
+	// is gone
+	crMemberNodes map[string][]*discovery.Node
 }
 
 // enforce compilation error
@@ -65,11 +181,18 @@ func New(logger log.Logger) *Kubernetes {
 		stopChan:      make(chan struct{}, 1),
 		isInitialized: atomic.NewBool(false),
 		logger:        logger,
+		knownPods:     make(map[string]*v1.Pod),
+		podsByNode:    make(map[string]map[string]struct{}),
+		leaseRenewals: make(map[string]time.Time),
+		podsSynced:    atomic.NewBool(false),
+		handlerPanics: atomic.NewInt64(0),
+		crMemberNodes: make(map[string][]*discovery.Node),
 	}
 	return k8
 }
 
-// EarliestNode returns the earliest node. This is based upon the node timestamp
+// EarliestNode returns the earliest node. This is based upon the node
+// timestamp, unless option.SortBy is set, in which case that ordering wins.
 func (k *Kubernetes) EarliestNode(ctx context.Context) (*discovery.Node, error) {
 	// fetch the list of Nodes
 	nodes, err := k.Nodes(ctx)
@@ -77,80 +200,146 @@ func (k *Kubernetes) EarliestNode(ctx context.Context) (*discovery.Node, error)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get the earliest node")
 	}
-	// let us sort the nodes by their timestamp
+	// no node matched the configured selector
+	if len(nodes) == 0 {
+		return nil, errors.New("no node found")
+	}
+	// let us sort the nodes, by their timestamp unless a custom order was configured
+	k.sortNodes(nodes)
+	// return the first element in the sorted list
+	return nodes[0], nil
+}
+
+// sortNodes orders nodes in place, using option.SortBy when configured and
+// falling back to the default timestamp ordering otherwise.
+func (k *Kubernetes) sortNodes(nodes []*discovery.Node) {
+	if k.option.SortBy != nil {
+		sort.Sort(k.option.SortBy(nodes))
+		return
+	}
 	sort.SliceStable(nodes, func(i, j int) bool {
 		return nodes[i].Timestamp() < nodes[j].Timestamp()
 	})
-	// return the first element in the sorted list
-	return nodes[0], nil
 }
 
-// Nodes returns the list of Nodes at a given time
+// Nodes returns the list of Nodes at a given time. It serves from the pod
+// informer cache whenever that cache is synced, and otherwise falls through
+// to a field-selector-driven, paginated list against the API server so a
+// cache miss on a namespace with thousands of pods does not pull everything
+// over the wire at once.
 func (k *Kubernetes) Nodes(ctx context.Context) ([]*discovery.Node, error) {
 	// first check whether the actor system has started
 	if !k.isInitialized.Load() {
 		return nil, errors.New("kubernetes discovery engine not initialized")
 	}
 
-	// List all the pods based on the filters we requested
-	pods, err := k.k8sClient.CoreV1().Pods(k.option.NameSpace).List(ctx, k8meta.ListOptions{
-		LabelSelector: labels.SelectorFromSet(k.option.PodLabels).String(),
-	})
-	// panic when we cannot poll the pods
+	nodes, err := k.listNodes(ctx)
 	if err != nil {
-		// TODO maybe do not panic
-		// TODO figure out the best approach
-		k.logger.Panic(errors.Wrap(err, "failed to fetch kubernetes pods"))
+		return nil, err
 	}
+	k.sortNodes(nodes)
+	return nodes, nil
+}
 
-	nodes := make([]*discovery.Node, 0, pods.Size())
+// listNodes resolves the current set of nodes, preferring the informer cache
+// and falling through to the paginated server-side list.
+func (k *Kubernetes) listNodes(ctx context.Context) ([]*discovery.Node, error) {
+	// fast path: serve straight from the informer cache when it is synced
+	if lister := k.getPodLister(); k.podsSynced.Load() && lister != nil {
+		selector := labels.SelectorFromSet(k.option.PodLabels)
+		pods, err := lister.Pods(k.option.NameSpace).List(selector)
+		if err == nil {
+			return k.nodesFromPods(pods), nil
+		}
+		// fall through to the paginated path on a cache read error
+	}
+
+	return k.listNodesPaginated(ctx)
+}
 
-	// iterate the pods list and only the one that are running
+// setPodLister publishes the lister backing the informer cache fast path
+// under k.mu, so a concurrent read in listNodes never races the write that
+// happens as the informer starts.
+func (k *Kubernetes) setPodLister(lister corelisters.PodLister) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.podLister = lister
+}
+
+// getPodLister reads the lister set by setPodLister under k.mu.
+func (k *Kubernetes) getPodLister() corelisters.PodLister {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.podLister
+}
+
+// listNodesPaginated walks the API server page by page, pushing both the
+// label selector and a status.phase=Running field selector down to the
+// server so non-running pods never come back over the wire.
+func (k *Kubernetes) listNodesPaginated(ctx context.Context) ([]*discovery.Node, error) {
+	pageSize := k.option.NodesPageSize
+	if pageSize <= 0 {
+		pageSize = defaultNodesPageSize
+	}
+
+	fieldSelector := fields.OneTermEqualSelector("status.phase", string(v1.PodRunning)).String()
+	labelSelector := labels.SelectorFromSet(k.option.PodLabels).String()
+
+	nodes := make([]*discovery.Node, 0, pageSize)
+	continueToken := ""
+	for {
+		pods, err := k.k8sClient.CoreV1().Pods(k.option.NameSpace).List(ctx, k8meta.ListOptions{
+			LabelSelector: labelSelector,
+			FieldSelector: fieldSelector,
+			Limit:         pageSize,
+			Continue:      continueToken,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch kubernetes pods")
+		}
+
+		nodes = append(nodes, k.nodesFromPods(podPointers(pods.Items))...)
+
+		continueToken = pods.Continue
+		if continueToken == "" {
+			break
+		}
+	}
+	return nodes, nil
+}
+
+// nodesFromPods filters pods down to the ones that are running, ready and
+// expose the configured port, turning each into a discovery.Node
+func (k *Kubernetes) nodesFromPods(pods []*v1.Pod) []*discovery.Node {
+	nodes := make([]*discovery.Node, 0, len(pods))
 MainLoop:
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		// only consider running pods
 		if pod.Status.Phase != v1.PodRunning {
 			continue MainLoop
 		}
-		// If there is a Ready condition available, we need that to be true.
-		// If no ready condition is set, then we accept this pod regardless.
-		for _, condition := range pod.Status.Conditions {
-			// ignore pod that is not in ready state
-			if condition.Type == v1.PodReady && condition.Status != v1.ConditionTrue {
-				continue MainLoop
-			}
-		}
-
-		// create a variable holding the node
-		var node *discovery.Node
-		// iterate the pod containers and find the named port
-		for i := 0; i < len(pod.Spec.Containers) && node == nil; i++ {
-			// let us get the container
-			container := pod.Spec.Containers[i]
-			// iterate the container ports
-			for _, port := range container.Ports {
-				// find the mapping port
-				if port.Name == k.option.PortName {
-					// create the node object
-					node = discovery.NewNode(
-						pod.GetName(),
-						pod.Status.PodIP,
-						port.ContainerPort,
-						pod.Status.StartTime.Time.UnixMilli(),
-						nil,
-					)
-					break
-				}
-			}
+		// ignore pods that are not ready, whether by the standard Ready
+		// condition or a readiness gate declared on the pod spec
+		if !isPodReady(pod) {
+			continue MainLoop
 		}
-		// continue the loop when we did not find any node
+		node := k.toNode(pod)
 		if node == nil {
 			continue MainLoop
 		}
-		// add the node to the list of nodes
 		nodes = append(nodes, node)
 	}
-	return nodes, nil
+	return nodes
+}
+
+// podPointers adapts a []v1.Pod slice, as returned by the typed client, to
+// the []*v1.Pod shape used by the cache-backed lister path
+func podPointers(pods []v1.Pod) []*v1.Pod {
+	out := make([]*v1.Pod, len(pods))
+	for i := range pods {
+		out[i] = &pods[i]
+	}
+	return out
 }
 
 // Watch returns event based upon node lifecycle
@@ -161,6 +350,13 @@ func (k *Kubernetes) Watch(ctx context.Context) (<-chan discovery.Event, error)
 	}
 	// run the watcher
 	go k.watchPods()
+	// watch the node leases as a complementary liveness signal
+	go k.watchLeases()
+	// watch the GoAktCluster custom resource when the CRD-backed membership
+	// source is enabled, in addition to the raw pod label selector
+	if k.option.UseCRD {
+		go k.watchCluster()
+	}
 	return k.publicChan, nil
 }
 
@@ -203,6 +399,17 @@ func (k *Kubernetes) Start(ctx context.Context, meta discovery.Meta) error {
 	if err := k.setOptions(meta); err != nil {
 		return errors.Wrap(err, "failed to instantiate the kubernetes discovery provider")
 	}
+
+	// when the CRD-backed membership source is enabled, also build a dynamic
+	// client so we can run an informer against the GoAktCluster resource
+	if k.option.UseCRD {
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the k8s dynamic client api")
+		}
+		k.dynamicClient = dynamicClient
+	}
+
 	// set initialized
 	k.isInitialized = atomic.NewBool(true)
 	return nil
@@ -216,11 +423,29 @@ func (k *Kubernetes) Stop() error {
 	}
 	// stop the watchers
 	close(k.stopChan)
-	// close the public channel
+	// taking the write lock waits for every in-flight sendEvent to finish its
+	// send before we close publicChan, so no goroutine can ever send on it
+	// after it is closed
+	k.closeMu.Lock()
+	defer k.closeMu.Unlock()
+	k.closed = true
 	close(k.publicChan)
 	return nil
 }
 
+// sendEvent publishes event on publicChan, unless Stop has already closed it.
+// Every publicChan send in this provider must go through sendEvent instead of
+// sending directly, since handler callbacks, backoff loops and sweep loops
+// all run concurrently with Stop with no other synchronization between them.
+func (k *Kubernetes) sendEvent(event discovery.Event) {
+	k.closeMu.RLock()
+	defer k.closeMu.RUnlock()
+	if k.closed {
+		return
+	}
+	k.publicChan <- event
+}
+
 // handlePodAdded is called when a new pod is added
 func (k *Kubernetes) handlePodAdded(pod *v1.Pod) {
 	// acquire the lock
@@ -237,13 +462,22 @@ func (k *Kubernetes) handlePodAdded(pod *v1.Pod) {
 	if node == nil {
 		return
 	}
+	// remember the pod so a lease expiry or a flipped readiness gate can
+	// later resolve back to this node
+	k.knownPods[pod.GetName()] = pod
+	k.trackPodNode(pod)
 	// here we find a node let us raise the node registered event
 	event := &discovery.NodeAdded{Node: node}
 	// add to the channel
-	k.publicChan <- event
+	k.sendEvent(event)
 }
 
-// handlePodUpdated is called when a pod is updated
+// handlePodUpdated is called when a pod is updated. A pod can transition
+// straight from ready to not-ready while staying Running (a flipped
+// readiness gate, for instance), in which case toNode(pod) now returns nil;
+// rather than silently dropping the event, that is treated as the pod
+// leaving the node set and raises NodeRemoved instead of waiting for the pod
+// to actually terminate.
 func (k *Kubernetes) handlePodUpdated(old *v1.Pod, pod *v1.Pod) {
 	// ignore the pod when it is not running
 	if pod.Status.Phase != v1.PodRunning {
@@ -257,31 +491,126 @@ func (k *Kubernetes) handlePodUpdated(old *v1.Pod, pod *v1.Pod) {
 	oldNode := k.toNode(old)
 	// get the new node
 	node := k.toNode(pod)
-	// continue the loop when we did not find any node
+
 	if node == nil {
+		// the pod went from ready to not-ready without leaving Running; if we
+		// had already turned it into a node, raise NodeRemoved now instead of
+		// waiting for the pod itself to transition
+		if _, known := k.knownPods[pod.GetName()]; known {
+			delete(k.knownPods, pod.GetName())
+			k.untrackPodNode(old)
+			if oldNode != nil {
+				k.sendEvent(&discovery.NodeRemoved{Node: oldNode})
+			}
+		}
 		return
 	}
+
 	// here we find a node let us raise the node modified event
+	k.knownPods[pod.GetName()] = pod
+	k.untrackPodNode(old)
+	k.trackPodNode(pod)
 	event := &discovery.NodeModified{
 		Node:    node,
 		Current: oldNode,
 	}
 	// add to the channel
-	k.publicChan <- event
+	k.sendEvent(event)
 }
 
-// handlePodDeleted is called when pod is deleted
+// handlePodDeleted is called when pod is deleted. A pod reaching DeleteFunc
+// may already be not-ready (the same readiness-gate transition
+// handlePodUpdated reacts to), which would make toNode(pod) return nil even
+// though it backed a real node; fall back to the last known-good version of
+// the pod before giving up, and never raise a NodeRemoved with a nil Node.
 func (k *Kubernetes) handlePodDeleted(pod *v1.Pod) {
 	// acquire the lock
 	k.mu.Lock()
 	// release the lock
 	defer k.mu.Unlock()
-	// get the new node
 	node := k.toNode(pod)
+	if node == nil {
+		if lastKnown, ok := k.knownPods[pod.GetName()]; ok {
+			node = k.toNode(lastKnown)
+		}
+	}
+	// forget about the pod, it no longer backs a node
+	delete(k.knownPods, pod.GetName())
+	k.untrackPodNode(pod)
+	// a NodeRemoved with a nil Node would nil-deref in consumers, so only
+	// raise the event when we actually resolved one
+	if node == nil {
+		return
+	}
 	// here we find a node let us raise the node removed event
 	event := &discovery.NodeRemoved{Node: node}
 	// add to the channel
-	k.publicChan <- event
+	k.sendEvent(event)
+}
+
+// trackPodNode records that pod is scheduled on pod.Spec.NodeName, callers
+// must hold k.mu.
+func (k *Kubernetes) trackPodNode(pod *v1.Pod) {
+	nodeName := pod.Spec.NodeName
+	if nodeName == "" {
+		return
+	}
+	pods, ok := k.podsByNode[nodeName]
+	if !ok {
+		pods = make(map[string]struct{})
+		k.podsByNode[nodeName] = pods
+	}
+	pods[pod.GetName()] = struct{}{}
+}
+
+// untrackPodNode forgets that pod is scheduled on pod.Spec.NodeName, callers
+// must hold k.mu.
+func (k *Kubernetes) untrackPodNode(pod *v1.Pod) {
+	nodeName := pod.Spec.NodeName
+	if nodeName == "" {
+		return
+	}
+	pods, ok := k.podsByNode[nodeName]
+	if !ok {
+		return
+	}
+	delete(pods, pod.GetName())
+	if len(pods) == 0 {
+		delete(k.podsByNode, nodeName)
+	}
+}
+
+// handleLeaseExpired is called once a Kubernetes Node's lease has gone
+// unrenewed for longer than LeaseDurationSeconds. Every pod we know to be
+// scheduled on that node may still report itself as Running, but we no
+// longer trust any of them to be reachable.
+func (k *Kubernetes) handleLeaseExpired(nodeName string) {
+	// acquire the lock
+	k.mu.Lock()
+	// release the lock
+	defer k.mu.Unlock()
+
+	// we only care about nodes that host pods we already turned into nodes
+	podNames, ok := k.podsByNode[nodeName]
+	if !ok || len(podNames) == 0 {
+		return
+	}
+
+	for podName := range podNames {
+		pod, ok := k.knownPods[podName]
+		if !ok {
+			continue
+		}
+
+		node := k.toNode(pod)
+		// the pod is no longer considered reachable, remove it without
+		// waiting for the pod itself to transition
+		delete(k.knownPods, podName)
+		if node != nil {
+			k.sendEvent(&discovery.NodeRemoved{Node: node})
+		}
+	}
+	delete(k.podsByNode, nodeName)
 }
 
 // setOptions sets the kubernetes option
@@ -312,22 +641,114 @@ func (k *Kubernetes) setOptions(meta discovery.Meta) (err error) {
 	if err != nil {
 		return err
 	}
+
+	// the lease namespace and lease duration are optional, fall back to the
+	// kubelet node-lease defaults when they are not provided
+	option.LeaseNamespace = defaultLeaseNamespace
+	if leaseNamespace, err := meta.GetString(LeaseNamespace); err == nil && leaseNamespace != "" {
+		option.LeaseNamespace = leaseNamespace
+	}
+	option.LeaseDurationSeconds = defaultLeaseDurationSeconds
+	if leaseDuration, err := meta.GetInt64(LeaseDurationSeconds); err == nil && leaseDuration > 0 {
+		option.LeaseDurationSeconds = leaseDuration
+	}
+
+	// the CRD-backed membership source is opt-in and falls back to the
+	// built-in GoAktCluster group/version/resource when not fully specified
+	if useCRD, err := meta.GetBool(UseCRD); err == nil {
+		option.UseCRD = useCRD
+	}
+	group := defaultCRDGroup
+	if v, err := meta.GetString(CRDGroup); err == nil && v != "" {
+		group = v
+	}
+	version := defaultCRDVersion
+	if v, err := meta.GetString(CRDVersion); err == nil && v != "" {
+		version = v
+	}
+	resource := defaultCRDResource
+	if v, err := meta.GetString(CRDResource); err == nil && v != "" {
+		resource = v
+	}
+	option.CRDGroupVersionResource = schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	option.CRDName, _ = meta.GetString(CRDName)
+
+	// the server-side list page size is optional and defaults to defaultNodesPageSize
+	option.NodesPageSize = defaultNodesPageSize
+	if pageSize, err := meta.GetInt64(NodesPageSize); err == nil && pageSize > 0 {
+		option.NodesPageSize = pageSize
+	}
+
+	// the informer sync retry backoff cap is optional and defaults to defaultMaxSyncBackoff
+	option.MaxSyncBackoff = defaultMaxSyncBackoff
+	if maxBackoffSeconds, err := meta.GetInt64(MaxSyncBackoffSeconds); err == nil && maxBackoffSeconds > 0 {
+		option.MaxSyncBackoff = time.Duration(maxBackoffSeconds) * time.Second
+	}
+
+	// stable identity is optional and only adds the pod_uid tag; the node ID
+	// stays the pod name either way
+	if stableIdentity, err := meta.GetBool(StableIdentity); err == nil {
+		option.StableIdentity = stableIdentity
+	}
+
 	// in case none of the above extraction fails then set the option
 	k.option = option
 	return nil
 }
 
-// toNode takes a kubernetes pod and returns a Node
-func (k *Kubernetes) toNode(pod *v1.Pod) *discovery.Node {
+// isPodReady reports whether the pod satisfies both the standard PodReady
+// condition and every readiness gate declared on its spec. Readiness gates
+// let a pod be marked Running while still failing a user-defined readiness
+// check (e.g. traffic warm-up), so both signals must agree before we treat
+// the pod as a usable node.
+func isPodReady(pod *v1.Pod) bool {
+	// index the conditions once so readiness gates can be resolved by type
+	conditions := make(map[v1.PodConditionType]v1.ConditionStatus, len(pod.Status.Conditions))
+	for _, condition := range pod.Status.Conditions {
+		conditions[condition.Type] = condition.Status
+	}
+
 	// If there is a Ready condition available, we need that to be true.
 	// If no ready condition is set, then we accept this pod regardless.
-	for _, condition := range pod.Status.Conditions {
-		// ignore pod that is not in ready state
-		if condition.Type == v1.PodReady && condition.Status != v1.ConditionTrue {
-			return nil
+	if status, ok := conditions[v1.PodReady]; ok && status != v1.ConditionTrue {
+		return false
+	}
+
+	// every readiness gate must resolve to a condition that is currently true
+	for _, gate := range pod.Spec.ReadinessGates {
+		if conditions[gate.ConditionType] != v1.ConditionTrue {
+			return false
 		}
 	}
 
+	return true
+}
+
+// toNode takes a kubernetes pod and returns a Node
+func (k *Kubernetes) toNode(pod *v1.Pod) *discovery.Node {
+	// a pod that is not ready, whether because of the standard Ready
+	// condition or one of its readiness gates, is not a usable node
+	if !isPodReady(pod) {
+		return nil
+	}
+
+	// surface the controlling owner, and the StatefulSet ordinal when
+	// applicable, so WithPartitionHasher can map partitions to stable
+	// ordinals instead of pod IPs that get reshuffled on every restart
+	tags := make(map[string]string)
+	if kind, name, ok := controllerOwnerReference(pod); ok {
+		tags["owner_kind"] = kind
+		tags["owner_name"] = name
+		if kind == "StatefulSet" {
+			if ordinal, ok := statefulSetOrdinal(pod.GetName()); ok {
+				tags["ordinal"] = strconv.Itoa(ordinal)
+			}
+		}
+	}
+	if k.option.StableIdentity {
+		tags["pod_uid"] = string(pod.GetUID())
+	}
+
 	// create a variable holding the node
 	var node *discovery.Node
 	// iterate the pod containers and find the named port
@@ -338,13 +759,13 @@ func (k *Kubernetes) toNode(pod *v1.Pod) *discovery.Node {
 		for _, port := range container.Ports {
 			// find the mapping port
 			if port.Name == k.option.PortName {
-				// create the node object
+				// create the node object, identified by pod name
 				node = discovery.NewNode(
 					pod.GetName(),
 					pod.Status.PodIP,
 					port.ContainerPort,
 					pod.Status.StartTime.Time.UnixMilli(),
-					nil,
+					tags,
 				)
 				break
 			}
@@ -354,9 +775,120 @@ func (k *Kubernetes) toNode(pod *v1.Pod) *discovery.Node {
 	return node
 }
 
-// watchPods keeps a watch on kubernetes pods activities and emit
-// respective event when needed
+// controllerOwnerReference returns the kind and name of the pod's controlling
+// owner reference (StatefulSet, Deployment, ReplicaSet, ...), if any.
+func controllerOwnerReference(pod *v1.Pod) (kind string, name string, ok bool) {
+	for _, ref := range pod.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			return ref.Kind, ref.Name, true
+		}
+	}
+	return "", "", false
+}
+
+// statefulSetOrdinal parses the ordinal suffix kubernetes appends to the
+// name of every pod backed by a StatefulSet, e.g. "my-set-3" yields 3.
+func statefulSetOrdinal(podName string) (int, bool) {
+	idx := strings.LastIndex(podName, "-")
+	if idx < 0 || idx == len(podName)-1 {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(podName[idx+1:])
+	if err != nil {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// handleCrash recovers a panic raised by fn, logs it through k.logger and
+// bumps the handler panic counter instead of letting it bring down the
+// watcher goroutine. This mirrors the util.HandleCrash pattern used by the
+// Kubernetes endpoint controller to keep informer event handlers crash-safe.
+func (k *Kubernetes) handleCrash(handler string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			k.handlerPanics.Inc()
+			k.logger.Error(errors.Errorf("recovered from panic in %s handler: %v", handler, r))
+		}
+	}()
+	fn()
+}
+
+// watchPods keeps a watch on kubernetes pods activities and emits the
+// respective event when needed. It is crash-safe: event handlers recover
+// from panics via handleCrash, and a failed initial sync no longer fatals
+// the process, instead retrying with exponential backoff (capped at
+// option.MaxSyncBackoff) and emitting a discovery.ProviderDegraded event so
+// the actor system can react. A watchdog goroutine restarts the informer if
+// it later falls out of sync, e.g. after an apiserver outage.
 func (k *Kubernetes) watchPods() {
+	maxBackoff := k.option.MaxSyncBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxSyncBackoff
+	}
+	backoff := time.Second
+
+	for {
+		select {
+		case <-k.stopChan:
+			return
+		default:
+		}
+
+		synced, informer, cancel := k.runPodsInformer()
+		if synced {
+			go k.watchPodsWatchdog(informer, cancel)
+			return
+		}
+
+		// this attempt never synced, stop its informer goroutine before
+		// retrying so a failed attempt never outlives its backoff sleep
+		cancel()
+
+		k.logger.Error(errors.Errorf("pod informer cache failed to sync, retrying in %s", backoff))
+		k.sendEvent(&discovery.ProviderDegraded{Reason: "pod informer cache failed to sync"})
+
+		select {
+		case <-k.stopChan:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// newInformerStopChan returns a stop channel that closes either when k.stopChan
+// closes or when the returned cancel func is called, whichever comes first.
+// Each informer run gets its own, so a restart can close the prior run's
+// channel and stop its goroutine without tearing down k.stopChan itself.
+func (k *Kubernetes) newInformerStopChan() (stopCh chan struct{}, cancel func()) {
+	stopCh = make(chan struct{})
+	done := make(chan struct{})
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() { close(done) })
+	}
+	go func() {
+		select {
+		case <-k.stopChan:
+		case <-done:
+		}
+		close(stopCh)
+	}()
+	return stopCh, cancel
+}
+
+// runPodsInformer runs a single attempt at starting the pod informer and
+// waiting for its cache to sync, returning whether it synced, the informer
+// instance so a watchdog can keep an eye on it afterwards, and a cancel func
+// that stops this specific run without affecting k.stopChan.
+func (k *Kubernetes) runPodsInformer() (bool, cache.SharedIndexInformer, func()) {
+	stopCh, cancel := k.newInformerStopChan()
+
 	// create the k8 informer factory
 	factory := informers.NewSharedInformerFactoryWithOptions(
 		k.k8sClient,
@@ -366,7 +898,9 @@ func (k *Kubernetes) watchPods() {
 			options.LabelSelector = labels.SelectorFromSet(k.option.PodLabels).String()
 		}))
 	// create the pods informer instance
-	informer := factory.Core().V1().Pods().Informer()
+	podsInformer := factory.Core().V1().Pods()
+	informer := podsInformer.Informer()
+	k.setPodLister(podsInformer.Lister())
 	synced := false
 	mux := &sync.RWMutex{}
 	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -376,11 +910,12 @@ func (k *Kubernetes) watchPods() {
 			if !synced {
 				return
 			}
-
-			// Handler logic
-			pod := obj.(*v1.Pod)
-			// handle the newly added pod
-			k.handlePodAdded(pod)
+			k.handleCrash("AddFunc", func() {
+				// Handler logic
+				pod := obj.(*v1.Pod)
+				// handle the newly added pod
+				k.handlePodAdded(pod)
+			})
 		},
 		UpdateFunc: func(current, node any) {
 			mux.RLock()
@@ -388,11 +923,153 @@ func (k *Kubernetes) watchPods() {
 			if !synced {
 				return
 			}
+			k.handleCrash("UpdateFunc", func() {
+				// Handler logic
+				old := current.(*v1.Pod)
+				pod := node.(*v1.Pod)
+				k.handlePodUpdated(old, pod)
+			})
+		},
+		DeleteFunc: func(obj any) {
+			mux.RLock()
+			defer mux.RUnlock()
+			if !synced {
+				return
+			}
+			k.handleCrash("DeleteFunc", func() {
+				// Handler logic
+				pod := obj.(*v1.Pod)
+				// handle the newly added pod
+				k.handlePodDeleted(pod)
+			})
+		},
+	})
+	if err != nil {
+		return false, nil, cancel
+	}
+
+	// run the informer against this attempt's own stop channel
+	go informer.Run(stopCh)
+
+	// wait for caches to sync
+	isSynced := cache.WaitForCacheSync(stopCh, informer.HasSynced)
+	mux.Lock()
+	synced = isSynced
+	mux.Unlock()
+	k.podsSynced.Store(isSynced)
+
+	return isSynced, informer, cancel
+}
+
+// watchPodsWatchdog restarts the pod informer if it ever falls back out of
+// sync after having successfully synced once, e.g. following an apiserver
+// outage. cancel stops the informer backing this watchdog; it is called
+// before every restart so the stale informer's goroutine never leaks.
+func (k *Kubernetes) watchPodsWatchdog(informer cache.SharedIndexInformer, cancel func()) {
+	ticker := time.NewTicker(5 * time.Second) // TODO make it configurable
+	defer ticker.Stop()
+	for {
+		select {
+		case <-k.stopChan:
+			cancel()
+			return
+		case <-ticker.C:
+			if !informer.HasSynced() {
+				k.podsSynced.Store(false)
+				k.sendEvent(&discovery.ProviderDegraded{Reason: "pod informer lost sync"})
+				cancel()
+				go k.watchPods()
+				return
+			}
+		}
+	}
+}
+
+// HasSynced reports whether the pod informer cache is currently synced. It
+// satisfies discovery.SyncChecker, letting an Aggregator treat this provider
+// as a priority/failover primary.
+func (k *Kubernetes) HasSynced() bool {
+	return k.podsSynced.Load()
+}
 
-			// Handler logic
-			old := current.(*v1.Pod)
-			pod := node.(*v1.Pod)
-			k.handlePodUpdated(old, pod)
+// watchLeases keeps a watch on the coordination.k8s.io/v1 Lease objects that
+// back each pod's liveness and reacts as soon as one goes stale, rather than
+// waiting for the pod itself to be marked NotReady. Like watchPods, a failed
+// initial sync never fatals the process: it retries with exponential
+// backoff (capped at option.MaxSyncBackoff) and emits a
+// discovery.ProviderDegraded event instead.
+func (k *Kubernetes) watchLeases() {
+	maxBackoff := k.option.MaxSyncBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxSyncBackoff
+	}
+	backoff := time.Second
+
+	for {
+		select {
+		case <-k.stopChan:
+			return
+		default:
+		}
+
+		synced, cancel := k.runLeasesInformer()
+		if synced {
+			k.watchLeaseSweeps(cancel)
+			return
+		}
+
+		cancel()
+		k.logger.Error(errors.Errorf("lease informer cache failed to sync, retrying in %s", backoff))
+		k.sendEvent(&discovery.ProviderDegraded{Reason: "lease informer cache failed to sync"})
+
+		select {
+		case <-k.stopChan:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// runLeasesInformer runs a single attempt at starting the lease informer and
+// waiting for its cache to sync, returning whether it synced and a cancel
+// func that stops this specific run without affecting k.stopChan.
+func (k *Kubernetes) runLeasesInformer() (bool, func()) {
+	stopCh, cancel := k.newInformerStopChan()
+
+	// create the k8 informer factory scoped to the lease namespace
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		k.k8sClient,
+		10*time.Minute, // TODO make it configurable
+		informers.WithNamespace(k.option.LeaseNamespace))
+	// create the lease informer instance
+	informer := factory.Coordination().V1().Leases().Informer()
+	synced := false
+	mux := &sync.RWMutex{}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			mux.RLock()
+			defer mux.RUnlock()
+			if !synced {
+				return
+			}
+			k.handleCrash("lease AddFunc", func() {
+				k.recordLeaseRenewal(obj.(*coordinationv1.Lease))
+			})
+		},
+		UpdateFunc: func(_, node any) {
+			mux.RLock()
+			defer mux.RUnlock()
+			if !synced {
+				return
+			}
+			k.handleCrash("lease UpdateFunc", func() {
+				k.recordLeaseRenewal(node.(*coordinationv1.Lease))
+			})
 		},
 		DeleteFunc: func(obj any) {
 			mux.RLock()
@@ -400,15 +1077,170 @@ func (k *Kubernetes) watchPods() {
 			if !synced {
 				return
 			}
+			k.handleCrash("lease DeleteFunc", func() {
+				lease := obj.(*coordinationv1.Lease)
+				k.handleLeaseExpired(lease.GetName())
+			})
+		},
+	})
+	if err != nil {
+		return false, cancel
+	}
+
+	// run the informer against this attempt's own stop channel
+	go informer.Run(stopCh)
+
+	// wait for caches to sync
+	isSynced := cache.WaitForCacheSync(stopCh, informer.HasSynced)
+	mux.Lock()
+	synced = isSynced
+	mux.Unlock()
+
+	return isSynced, cancel
+}
+
+// watchLeaseSweeps periodically sweeps the tracked leases and evicts any
+// node that has gone unrenewed for longer than LeaseDurationSeconds, until
+// k.stopChan closes, at which point it stops the lease informer via cancel.
+func (k *Kubernetes) watchLeaseSweeps(cancel func()) {
+	defer cancel()
+	leaseDuration := time.Duration(k.option.LeaseDurationSeconds) * time.Second
+	ticker := time.NewTicker(leaseDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-k.stopChan:
+			return
+		case <-ticker.C:
+			k.sweepExpiredLeases(leaseDuration)
+		}
+	}
+}
+
+// recordLeaseRenewal stores the last renew time reported by a node lease.
+// Lease objects in the kube-node-lease namespace are named after the
+// Kubernetes Node they represent, not after any pod, so this key is a node
+// name and must be resolved to pods via podsByNode before use.
+func (k *Kubernetes) recordLeaseRenewal(lease *coordinationv1.Lease) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if lease.Spec.RenewTime != nil {
+		k.leaseRenewals[lease.GetName()] = lease.Spec.RenewTime.Time
+	}
+}
+
+// sweepExpiredLeases walks the tracked lease renewals and evicts every pod
+// scheduled on a node whose lease has not been renewed within the configured
+// duration
+func (k *Kubernetes) sweepExpiredLeases(leaseDuration time.Duration) {
+	k.mu.Lock()
+	expired := make([]string, 0)
+	now := time.Now()
+	for nodeName, renewedAt := range k.leaseRenewals {
+		if now.Sub(renewedAt) > leaseDuration {
+			expired = append(expired, nodeName)
+		}
+	}
+	k.mu.Unlock()
+
+	for _, nodeName := range expired {
+		k.handleLeaseExpired(nodeName)
+	}
+}
+
+// watchCluster keeps a watch on the GoAktCluster custom resource and
+// translates its member groups into discovery events, letting operators
+// manage topology declaratively instead of through raw pod label selectors.
+// This is an opt-in, background feature (UseCRD), so a failed initial sync
+// never fatals the process: it retries with exponential backoff (capped at
+// option.MaxSyncBackoff) and emits a discovery.ProviderDegraded event,
+// mirroring watchPods.
+func (k *Kubernetes) watchCluster() {
+	maxBackoff := k.option.MaxSyncBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxSyncBackoff
+	}
+	backoff := time.Second
+
+	for {
+		select {
+		case <-k.stopChan:
+			return
+		default:
+		}
+
+		synced := k.runClusterInformer()
+		if synced {
+			return
+		}
+
+		k.logger.Error(errors.Errorf("GoAktCluster informer cache failed to sync, retrying in %s", backoff))
+		k.sendEvent(&discovery.ProviderDegraded{Reason: "GoAktCluster informer cache failed to sync"})
+
+		select {
+		case <-k.stopChan:
+			return
+		case <-time.After(backoff):
+		}
 
-			// Handler logic
-			pod := obj.(*v1.Pod)
-			// handle the newly added pod
-			k.handlePodDeleted(pod)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// runClusterInformer runs a single attempt at starting the GoAktCluster
+// informer and waiting for its cache to sync, returning whether it synced.
+func (k *Kubernetes) runClusterInformer() bool {
+	// create a dynamic informer factory scoped to the configured namespace
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactoryWithOptions(
+		k.dynamicClient,
+		10*time.Minute, // TODO make it configurable
+		k.option.NameSpace,
+		func(options *k8meta.ListOptions) {
+			if k.option.CRDName != "" {
+				options.FieldSelector = "metadata.name=" + k.option.CRDName
+			}
+		},
+	)
+	informer := factory.ForResource(k.option.CRDGroupVersionResource).Informer()
+	synced := false
+	mux := &sync.RWMutex{}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			mux.RLock()
+			defer mux.RUnlock()
+			if !synced {
+				return
+			}
+			k.handleClusterChanged(obj.(*unstructured.Unstructured))
+		},
+		UpdateFunc: func(_, node any) {
+			mux.RLock()
+			defer mux.RUnlock()
+			if !synced {
+				return
+			}
+			k.handleClusterChanged(node.(*unstructured.Unstructured))
+		},
+		DeleteFunc: func(obj any) {
+			mux.RLock()
+			defer mux.RUnlock()
+			if !synced {
+				return
+			}
+			cr := obj.(*unstructured.Unstructured)
+			// use the last-known member nodes for this CR rather than
+			// re-listing pods: the CR is gone, but its pods are very likely
+			// still Running, so a live list would not reflect a removal
+			for _, node := range k.popCRMemberNodes(cr.GetName()) {
+				k.sendEvent(&discovery.NodeRemoved{Node: node})
+			}
 		},
 	})
 	if err != nil {
-		return
+		return false
 	}
 
 	// run the informer
@@ -420,8 +1252,159 @@ func (k *Kubernetes) watchPods() {
 	synced = isSynced
 	mux.Unlock()
 
-	// caches failed to sync
-	if !synced {
-		k.logger.Fatal("caches failed to sync")
+	return isSynced
+}
+
+// handleClusterChanged resolves a GoAktCluster custom resource into nodes and
+// diffs them against the CR's previously cached members, so an Update that
+// shrinks or changes membership is reflected immediately instead of only on
+// Delete: new members raise NodeAdded, members whose node changed raise
+// NodeModified, and members that dropped out raise NodeRemoved. The result is
+// cached as the CR's last-known members, so a later Delete can still emit
+// NodeRemoved for exactly these nodes without re-listing pods. These events
+// share publicChan with the pod-sourced events and are not deduplicated
+// against them: the same (host, port) pair can be reported by both sources,
+// and a discovery.Aggregator forwards both unchanged.
+func (k *Kubernetes) handleClusterChanged(cr *unstructured.Unstructured) {
+	nodes := k.crMemberGroupNodes(cr)
+	old := k.popCRMemberNodes(cr.GetName())
+	k.setCRMemberNodes(cr.GetName(), nodes)
+
+	added, modified, removed := diffCRMembers(old, nodes)
+	for _, node := range added {
+		k.sendEvent(&discovery.NodeAdded{Node: node})
+	}
+	for _, pair := range modified {
+		k.sendEvent(&discovery.NodeModified{Node: pair.node, Current: pair.previous})
+	}
+	for _, node := range removed {
+		k.sendEvent(&discovery.NodeRemoved{Node: node})
+	}
+}
+
+// crNodeKey returns the dedupe/identity key used to diff a CR's member nodes
+// across updates, mirroring discovery.Aggregator's nodeKey.
+func crNodeKey(node *discovery.Node) string {
+	return fmt.Sprintf("%s:%d", node.Host(), node.Port())
+}
+
+// modifiedCRMember pairs a member's new node with the previous node it
+// replaces, as returned by diffCRMembers.
+type modifiedCRMember struct {
+	node     *discovery.Node
+	previous *discovery.Node
+}
+
+// diffCRMembers compares a GoAktCluster's previously cached member nodes
+// against its freshly resolved ones, keyed by crNodeKey, and splits the
+// result into members that are new, members whose node changed, and members
+// that dropped out of the CR.
+func diffCRMembers(old, current []*discovery.Node) (added []*discovery.Node, modified []modifiedCRMember, removed []*discovery.Node) {
+	oldByKey := make(map[string]*discovery.Node, len(old))
+	for _, node := range old {
+		oldByKey[crNodeKey(node)] = node
+	}
+
+	seen := make(map[string]struct{}, len(current))
+	for _, node := range current {
+		key := crNodeKey(node)
+		seen[key] = struct{}{}
+		previous, ok := oldByKey[key]
+		if !ok {
+			added = append(added, node)
+			continue
+		}
+		if previous.Timestamp() != node.Timestamp() {
+			modified = append(modified, modifiedCRMember{node: node, previous: previous})
+		}
+	}
+
+	for key, node := range oldByKey {
+		if _, ok := seen[key]; !ok {
+			removed = append(removed, node)
+		}
+	}
+
+	return added, modified, removed
+}
+
+// setCRMemberNodes records nodes as the last-known members of the CR named crName.
+func (k *Kubernetes) setCRMemberNodes(crName string, nodes []*discovery.Node) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.crMemberNodes[crName] = nodes
+}
+
+// popCRMemberNodes returns and forgets the last-known members of the CR
+// named crName, recorded by setCRMemberNodes.
+func (k *Kubernetes) popCRMemberNodes(crName string) []*discovery.Node {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	nodes := k.crMemberNodes[crName]
+	delete(k.crMemberNodes, crName)
+	return nodes
+}
+
+// crMemberGroupNodes lists the pods matched by each member group declared on
+// the GoAktCluster resource and turns them into nodes, tagging each one with
+// the group's roles/tags so downstream consumers can tell members apart.
+func (k *Kubernetes) crMemberGroupNodes(cr *unstructured.Unstructured) []*discovery.Node {
+	groups, found, err := unstructured.NestedSlice(cr.Object, "spec", "memberGroups")
+	if err != nil || !found {
+		return nil
+	}
+
+	nodes := make([]*discovery.Node, 0, len(groups))
+	for _, raw := range groups {
+		group, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		labelSelector, _ := group["labelSelector"].(string)
+		portName, _ := group["portName"].(string)
+		if portName == "" {
+			continue
+		}
+
+		tags := make(map[string]string)
+		if name, ok := group["name"].(string); ok {
+			tags["group"] = name
+		}
+		if roles, ok := group["roles"].([]any); ok {
+			for i, role := range roles {
+				if s, ok := role.(string); ok {
+					tags[fmt.Sprintf("role.%d", i)] = s
+				}
+			}
+		}
+
+		pods, err := k.k8sClient.CoreV1().Pods(k.option.NameSpace).List(context.Background(), k8meta.ListOptions{
+			LabelSelector: labelSelector,
+		})
+		if err != nil {
+			k.logger.Error(errors.Wrapf(err, "failed to list pods for GoAktCluster member group %q", group["name"]))
+			continue
+		}
+
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != v1.PodRunning || !isPodReady(&pod) {
+				continue
+			}
+			for _, container := range pod.Spec.Containers {
+				for _, port := range container.Ports {
+					if port.Name == portName {
+						nodes = append(nodes, discovery.NewNode(
+							pod.GetName(),
+							pod.Status.PodIP,
+							port.ContainerPort,
+							pod.Status.StartTime.Time.UnixMilli(),
+							tags,
+						))
+					}
+				}
+			}
+		}
 	}
+	return nodes
 }
@@ -0,0 +1,468 @@
+package kubernetes
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tochemey/goakt/discovery"
+	"github.com/tochemey/goakt/log"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsPodReady(t *testing.T) {
+	testCases := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "no ready condition set is accepted",
+			pod:  &v1.Pod{},
+			want: true,
+		},
+		{
+			name: "ready condition true",
+			pod: &v1.Pod{Status: v1.PodStatus{Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+			}}},
+			want: true,
+		},
+		{
+			name: "ready condition false",
+			pod: &v1.Pod{Status: v1.PodStatus{Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionFalse},
+			}}},
+			want: false,
+		},
+		{
+			name: "ready true but a readiness gate is false",
+			pod: &v1.Pod{
+				Spec: v1.PodSpec{ReadinessGates: []v1.PodReadinessGate{{ConditionType: "custom.io/warmed-up"}}},
+				Status: v1.PodStatus{Conditions: []v1.PodCondition{
+					{Type: v1.PodReady, Status: v1.ConditionTrue},
+					{Type: "custom.io/warmed-up", Status: v1.ConditionFalse},
+				}},
+			},
+			want: false,
+		},
+		{
+			name: "ready true and every readiness gate true",
+			pod: &v1.Pod{
+				Spec: v1.PodSpec{ReadinessGates: []v1.PodReadinessGate{{ConditionType: "custom.io/warmed-up"}}},
+				Status: v1.PodStatus{Conditions: []v1.PodCondition{
+					{Type: v1.PodReady, Status: v1.ConditionTrue},
+					{Type: "custom.io/warmed-up", Status: v1.ConditionTrue},
+				}},
+			},
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, isPodReady(tc.pod))
+		})
+	}
+}
+
+func runningReadyPod(name, nodeName string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PodSpec{
+			NodeName: nodeName,
+			Containers: []v1.Container{{
+				Name:  "app",
+				Ports: []v1.ContainerPort{{Name: "grpc", ContainerPort: 3000}},
+			}},
+		},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			PodIP:      "10.0.0.1",
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+			StartTime:  &metav1.Time{},
+		},
+	}
+}
+
+func TestHandleLeaseExpiredRemovesEveryPodOnTheNode(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc"}
+	go func() {
+		for range k.publicChan {
+		}
+	}()
+
+	podA := runningReadyPod("accounts-0", "ip-10-0-1-5")
+	podB := runningReadyPod("accounts-1", "ip-10-0-1-5")
+	podC := runningReadyPod("accounts-2", "ip-10-0-1-6")
+
+	k.handlePodAdded(podA)
+	k.handlePodAdded(podB)
+	k.handlePodAdded(podC)
+
+	// lease objects are named after the Node, not the pod
+	k.handleLeaseExpired("ip-10-0-1-5")
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	_, aKnown := k.knownPods["accounts-0"]
+	_, bKnown := k.knownPods["accounts-1"]
+	_, cKnown := k.knownPods["accounts-2"]
+	assert.False(t, aKnown)
+	assert.False(t, bKnown)
+	assert.True(t, cKnown)
+	_, nodeStillTracked := k.podsByNode["ip-10-0-1-5"]
+	assert.False(t, nodeStillTracked)
+}
+
+func TestHandleLeaseExpiredUnknownNodeIsNoop(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc"}
+	assert.NotPanics(t, func() {
+		k.handleLeaseExpired("some-other-node")
+	})
+}
+
+func TestHandlePodUpdatedEmitsNodeRemovedOnReadinessGateFlip(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc"}
+
+	events := make(chan discovery.Event, 1)
+	k.publicChan = events
+
+	pod := runningReadyPod("accounts-0", "ip-10-0-1-5")
+	pod.Spec.ReadinessGates = []v1.PodReadinessGate{{ConditionType: "custom.io/warmed-up"}}
+	pod.Status.Conditions = append(pod.Status.Conditions, v1.PodCondition{Type: "custom.io/warmed-up", Status: v1.ConditionTrue})
+	k.handlePodAdded(pod)
+	<-events // drain the NodeAdded raised by handlePodAdded
+
+	k.mu.Lock()
+	_, known := k.knownPods["accounts-0"]
+	k.mu.Unlock()
+	assert.True(t, known)
+
+	notReady := pod.DeepCopy()
+	notReady.Status.Conditions[1].Status = v1.ConditionFalse
+	k.handlePodUpdated(pod, notReady)
+
+	event := <-events
+	removed, ok := event.(*discovery.NodeRemoved)
+	if assert.True(t, ok) {
+		assert.Equal(t, "10.0.0.1", removed.Node.Host())
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	_, stillKnown := k.knownPods["accounts-0"]
+	assert.False(t, stillKnown)
+	_, nodeStillTracked := k.podsByNode["ip-10-0-1-5"]
+	assert.False(t, nodeStillTracked)
+}
+
+func TestHandlePodDeletedSkipsNilNodeWhenNeverKnown(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc"}
+
+	pod := runningReadyPod("accounts-0", "ip-10-0-1-5")
+	pod.Status.Conditions[0].Status = v1.ConditionFalse // unready at delete time, and never added
+
+	assert.NotPanics(t, func() {
+		k.handlePodDeleted(pod)
+	})
+}
+
+func TestHandlePodDeletedFallsBackToLastKnownNode(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc"}
+
+	events := make(chan discovery.Event, 1)
+	k.publicChan = events
+
+	pod := runningReadyPod("accounts-0", "ip-10-0-1-5")
+	k.handlePodAdded(pod)
+	<-events // drain the NodeAdded raised by handlePodAdded
+
+	unready := pod.DeepCopy()
+	unready.Status.Conditions[0].Status = v1.ConditionFalse
+	k.handlePodDeleted(unready)
+
+	event := <-events
+	removed, ok := event.(*discovery.NodeRemoved)
+	if assert.True(t, ok) {
+		assert.Equal(t, "10.0.0.1", removed.Node.Host())
+	}
+}
+
+func TestControllerOwnerReference(t *testing.T) {
+	isController := true
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "StatefulSet", Name: "accounts", Controller: &isController},
+			},
+		},
+	}
+	kind, name, ok := controllerOwnerReference(pod)
+	assert.True(t, ok)
+	assert.Equal(t, "StatefulSet", kind)
+	assert.Equal(t, "accounts", name)
+
+	_, _, ok = controllerOwnerReference(&v1.Pod{})
+	assert.False(t, ok)
+}
+
+func TestStatefulSetOrdinal(t *testing.T) {
+	testCases := []struct {
+		name        string
+		podName     string
+		wantOrdinal int
+		wantOK      bool
+	}{
+		{name: "valid ordinal", podName: "accounts-3", wantOrdinal: 3, wantOK: true},
+		{name: "no dash", podName: "accounts", wantOrdinal: 0, wantOK: false},
+		{name: "non numeric suffix", podName: "accounts-leader", wantOrdinal: 0, wantOK: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ordinal, ok := statefulSetOrdinal(tc.podName)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantOrdinal, ordinal)
+		})
+	}
+}
+
+func TestToNodeStableIdentityTagsUIDButKeepsNameAsID(t *testing.T) {
+	isController := true
+	pod := runningReadyPod("accounts-0", "ip-10-0-1-5")
+	pod.ObjectMeta.UID = "11111111-1111-1111-1111-111111111111"
+	pod.ObjectMeta.OwnerReferences = []metav1.OwnerReference{
+		{Kind: "StatefulSet", Name: "accounts", Controller: &isController},
+	}
+
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc", StableIdentity: true}
+
+	node := k.toNode(pod)
+	if assert.NotNil(t, node) {
+		assert.Equal(t, pod.GetName(), node.Name())
+		assert.Equal(t, pod.Status.PodIP, node.Host())
+		assert.EqualValues(t, 3000, node.Port())
+		assert.Equal(t, string(pod.GetUID()), node.Tags()["pod_uid"])
+	}
+}
+
+func TestToNodeWithoutStableIdentityOmitsUIDTag(t *testing.T) {
+	pod := runningReadyPod("accounts-0", "ip-10-0-1-5")
+	pod.ObjectMeta.UID = "11111111-1111-1111-1111-111111111111"
+
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc"}
+
+	node := k.toNode(pod)
+	if assert.NotNil(t, node) {
+		_, hasUIDTag := node.Tags()["pod_uid"]
+		assert.False(t, hasUIDTag)
+	}
+}
+
+func TestHandlePodUpdatedEmitsNodeModifiedNotRemovedOnRecreate(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc", StableIdentity: true}
+
+	events := make(chan discovery.Event, 1)
+	k.publicChan = events
+
+	original := runningReadyPod("accounts-0", "ip-10-0-1-5")
+	original.ObjectMeta.UID = "11111111-1111-1111-1111-111111111111"
+	k.handlePodAdded(original)
+	<-events // drain the NodeAdded raised by handlePodAdded
+
+	// a pod recreated with the same name gets a new UID, but keeps the node
+	// identified by name, so this must surface as a single NodeModified
+	// instead of a NodeRemoved followed by a NodeAdded
+	recreated := original.DeepCopy()
+	recreated.ObjectMeta.UID = "22222222-2222-2222-2222-222222222222"
+	k.handlePodUpdated(original, recreated)
+
+	event := <-events
+	modified, ok := event.(*discovery.NodeModified)
+	if assert.True(t, ok) {
+		assert.Equal(t, "22222222-2222-2222-2222-222222222222", modified.Node.Tags()["pod_uid"])
+	}
+}
+
+// byTimestamp is a sort.Interface used to exercise option.SortBy
+type byTimestamp []*discovery.Node
+
+func (b byTimestamp) Len() int      { return len(b) }
+func (b byTimestamp) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byTimestamp) Less(i, j int) bool {
+	return b[i].Timestamp() < b[j].Timestamp()
+}
+
+func TestSortNodesDefaultsToTimestampOrder(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{}
+
+	newer := discovery.NewNode("b", "10.0.0.2", 3000, 200, nil)
+	older := discovery.NewNode("a", "10.0.0.1", 3000, 100, nil)
+	nodes := []*discovery.Node{newer, older}
+
+	k.sortNodes(nodes)
+	assert.Equal(t, int64(100), nodes[0].Timestamp())
+	assert.Equal(t, int64(200), nodes[1].Timestamp())
+}
+
+func TestSortNodesHonorsCustomSortBy(t *testing.T) {
+	newer := discovery.NewNode("b", "10.0.0.2", 3000, 200, nil)
+	older := discovery.NewNode("a", "10.0.0.1", 3000, 100, nil)
+
+	k := New(log.DefaultLogger)
+	k.option = &Option{
+		SortBy: func(nodes []*discovery.Node) sort.Interface {
+			return sort.Reverse(byTimestamp(nodes))
+		},
+	}
+
+	nodes := []*discovery.Node{older, newer}
+	k.sortNodes(nodes)
+	assert.Equal(t, int64(200), nodes[0].Timestamp())
+	assert.Equal(t, int64(100), nodes[1].Timestamp())
+}
+
+func TestPodListerGetSetRoundTrips(t *testing.T) {
+	k := New(log.DefaultLogger)
+	assert.Nil(t, k.getPodLister())
+	k.setPodLister(nil)
+	assert.Nil(t, k.getPodLister())
+}
+
+func TestHandleCrashRecoversPanic(t *testing.T) {
+	k := New(log.DefaultLogger)
+	assert.NotPanics(t, func() {
+		k.handleCrash("test", func() { panic("boom") })
+	})
+	assert.EqualValues(t, 1, k.handlerPanics.Load())
+}
+
+func TestNewInformerStopChanClosesOnCancel(t *testing.T) {
+	k := New(log.DefaultLogger)
+	stopCh, cancel := k.newInformerStopChan()
+
+	select {
+	case <-stopCh:
+		t.Fatal("expected the informer stop channel to still be open")
+	default:
+	}
+
+	cancel()
+
+	select {
+	case <-stopCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected the informer stop channel to close after cancel")
+	}
+
+	// cancel must be idempotent
+	assert.NotPanics(t, cancel)
+}
+
+func TestCRMemberNodeCacheRoundTrips(t *testing.T) {
+	k := New(log.DefaultLogger)
+	nodes := []*discovery.Node{discovery.NewNode("accounts-0", "10.0.0.5", 3000, 100, nil)}
+
+	k.setCRMemberNodes("accounts", nodes)
+
+	k.mu.Lock()
+	assert.Equal(t, nodes, k.crMemberNodes["accounts"])
+	k.mu.Unlock()
+
+	popped := k.popCRMemberNodes("accounts")
+	assert.Equal(t, nodes, popped)
+
+	k.mu.Lock()
+	_, stillCached := k.crMemberNodes["accounts"]
+	k.mu.Unlock()
+	assert.False(t, stillCached)
+}
+
+func TestPopCRMemberNodesUnknownCRIsEmpty(t *testing.T) {
+	k := New(log.DefaultLogger)
+	assert.Empty(t, k.popCRMemberNodes("unknown"))
+}
+
+func TestDiffCRMembersClassifiesAddedModifiedAndRemoved(t *testing.T) {
+	stable := discovery.NewNode("accounts-0", "10.0.0.5", 3000, 100, nil)
+	recreated := discovery.NewNode("accounts-1", "10.0.0.6", 3000, 100, nil)
+	recreatedUpdated := discovery.NewNode("accounts-1", "10.0.0.6", 3000, 200, nil)
+	droppedOut := discovery.NewNode("accounts-2", "10.0.0.7", 3000, 100, nil)
+	newMember := discovery.NewNode("accounts-3", "10.0.0.8", 3000, 300, nil)
+
+	old := []*discovery.Node{stable, recreated, droppedOut}
+	current := []*discovery.Node{stable, recreatedUpdated, newMember}
+
+	added, modified, removed := diffCRMembers(old, current)
+
+	assert.Len(t, added, 1)
+	assert.Equal(t, newMember, added[0])
+
+	if assert.Len(t, modified, 1) {
+		assert.Equal(t, recreatedUpdated, modified[0].node)
+		assert.Equal(t, recreated, modified[0].previous)
+	}
+
+	assert.Len(t, removed, 1)
+	assert.Equal(t, droppedOut, removed[0])
+}
+
+func TestDiffCRMembersEmptyOldIsAllAdded(t *testing.T) {
+	node := discovery.NewNode("accounts-0", "10.0.0.5", 3000, 100, nil)
+
+	added, modified, removed := diffCRMembers(nil, []*discovery.Node{node})
+
+	assert.Equal(t, []*discovery.Node{node}, added)
+	assert.Empty(t, modified)
+	assert.Empty(t, removed)
+}
+
+func TestNewInformerStopChanClosesWithParent(t *testing.T) {
+	k := New(log.DefaultLogger)
+	stopCh, _ := k.newInformerStopChan()
+
+	close(k.stopChan)
+
+	select {
+	case <-stopCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected the informer stop channel to close when k.stopChan closes")
+	}
+}
+
+func TestStopDoesNotPanicWhileSendingEvent(t *testing.T) {
+	k := New(log.DefaultLogger)
+	k.option = &Option{PortName: "grpc"}
+	k.isInitialized.Store(true)
+
+	go func() {
+		for range k.publicChan {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k.sendEvent(&discovery.NodeAdded{Node: discovery.NewNode("a", "10.0.0.1", 3000, 100, nil)})
+		}(i)
+	}
+
+	assert.NotPanics(t, func() {
+		assert.NoError(t, k.Stop())
+	})
+	wg.Wait()
+}
@@ -0,0 +1,92 @@
+package discovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tochemey/goakt/log"
+	k8meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaderElectionConfig configures RunWithLeaderElection
+type LeaderElectionConfig struct {
+	// Namespace holds the namespace the Lease lock lives in
+	Namespace string
+	// Name is the name of the lock object
+	Name string
+	// Identity uniquely identifies this node in the election, e.g. the pod name
+	Identity string
+	// LeaseDuration is how long a leader's lease remains valid after its last renew
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader attempts to renew before giving up
+	RenewDeadline time.Duration
+	// RetryPeriod is how often non-leaders check whether the lock is free
+	RetryPeriod time.Duration
+}
+
+// RunWithLeaderElection blocks until ctx is done or this process loses
+// leadership of the GoAkt cluster. It runs onStartedLeading once this process
+// becomes the leader, and onStoppedLeading when it returns; after a loss of
+// leadership it does not campaign again. Leadership is coordinated through a
+// coordination.k8s.io/v1 Lease lock, mirroring the pattern used by the kubelet and
+// kube-controller-manager, so that only one node per cluster runs the
+// expensive peer-state loop.
+func RunWithLeaderElection(
+	ctx context.Context,
+	client kubernetes.Interface,
+	logger log.Logger,
+	config LeaderElectionConfig,
+	onStartedLeading func(context.Context),
+	onStoppedLeading func(),
+) error {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: k8meta.ObjectMeta{
+			Name:      config.Name,
+			Namespace: config.Namespace,
+		},
+		Client: client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: config.Identity,
+		},
+	}
+
+	leaseDuration := config.LeaseDuration
+	if leaseDuration <= 0 {
+		leaseDuration = 15 * time.Second
+	}
+	renewDeadline := config.RenewDeadline
+	if renewDeadline <= 0 {
+		renewDeadline = 10 * time.Second
+	}
+	retryPeriod := config.RetryPeriod
+	if retryPeriod <= 0 {
+		retryPeriod = 2 * time.Second
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: onStartedLeading,
+			OnStoppedLeading: onStoppedLeading,
+			OnNewLeader: func(identity string) {
+				if identity == config.Identity {
+					return
+				}
+				logger.Info("GoAkt cluster leader is now " + identity)
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the leader elector")
+	}
+
+	elector.Run(ctx)
+	return nil
+}